@@ -0,0 +1,63 @@
+package compress
+
+import (
+	"bytes"
+	"sync"
+)
+
+// defaultMaxPooledBufferSize 是放回对象池的 buffer/writer/reader 允许保留的
+// 最大"体积"（字节）。
+//
+// Proper usage of a sync.Pool requires each entry to have approximately
+// the same memory cost. To obtain this property when the stored type
+// contains a variably-sized buffer, we add a hard limit on the maximum buffer
+// to place back in the pool.
+//
+// See https://golang.org/issue/23199
+const defaultMaxPooledBufferSize = 64 << 10
+
+var (
+	maxPooledBufferSizeMu sync.RWMutex
+	maxPooledBufferSize   = defaultMaxPooledBufferSize
+)
+
+// SetMaxPooledBufferSize 设置放回各个对象池的条目允许保留的最大体积（字节）。
+// 处理单条超过该阈值的超大消息之后，对应的 buffer/writer/reader 会被直接丢弃
+// 而不是放回池中，避免一条病态的大消息把多 MB 的内存长期钉在池里。
+func SetMaxPooledBufferSize(n int) {
+	maxPooledBufferSizeMu.Lock()
+	defer maxPooledBufferSizeMu.Unlock()
+	maxPooledBufferSize = n
+}
+
+// maxPooledSize 返回当前配置的池化体积上限。
+func maxPooledSize() int {
+	maxPooledBufferSizeMu.RLock()
+	defer maxPooledBufferSizeMu.RUnlock()
+	return maxPooledBufferSize
+}
+
+// withinPooledSize 报告 size 是否不超过当前配置的池化体积上限，
+// 即 putBuffer/putSized 是否应当把对应的条目放回池中。
+func withinPooledSize(size int) bool {
+	return size <= maxPooledSize()
+}
+
+// putBuffer 将 buf 放回 pool，但当其底层容量超过 SetMaxPooledBufferSize
+// 配置的阈值时直接丢弃，由 GC 回收，而不是把一块超大内存长期留在池中。
+func putBuffer(pool *sync.Pool, buf *bytes.Buffer) {
+	if !withinPooledSize(buf.Cap()) {
+		return
+	}
+	pool.Put(buf)
+}
+
+// putSized 将 v 放回 pool，但当 size（本次处理的数据体积）超过阈值时丢弃。
+// 用于 flate/gzip/zlib 的 writer、reader 这类内部状态体积随输入增长、
+// 但不对外暴露 Cap() 的对象池。
+func putSized(pool *sync.Pool, v interface{}, size int) {
+	if !withinPooledSize(size) {
+		return
+	}
+	pool.Put(v)
+}
@@ -0,0 +1,41 @@
+package compress
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWithinPooledSize(t *testing.T) {
+	orig := maxPooledSize()
+	defer SetMaxPooledBufferSize(orig)
+	SetMaxPooledBufferSize(8)
+
+	if withinPooledSize(1024) {
+		t.Fatal("expected size 1024 to exceed an 8-byte threshold")
+	}
+	if !withinPooledSize(4) {
+		t.Fatal("expected size 4 to be within an 8-byte threshold")
+	}
+	if !withinPooledSize(8) {
+		t.Fatal("expected size equal to the threshold to be within it")
+	}
+}
+
+func TestCompressDecompressRoundTripWithSmallMaxPooledBufferSize(t *testing.T) {
+	orig := maxPooledSize()
+	defer SetMaxPooledBufferSize(orig)
+	SetMaxPooledBufferSize(16)
+
+	data := []byte("payload larger than the configured pooled buffer threshold, repeated several times over")
+	c, err := Compress(data)
+	if err != nil {
+		t.Fatalf("Compress: %v", err)
+	}
+	d, err := Decompress(c)
+	if err != nil {
+		t.Fatalf("Decompress: %v", err)
+	}
+	if !bytes.Equal(d, data) {
+		t.Fatal("round trip mismatch")
+	}
+}
@@ -0,0 +1,62 @@
+package compress
+
+import (
+	"io"
+	"sync"
+)
+
+// Codec 是一种压缩算法的抽象，既支持一次性的 []byte 压缩/解压，
+// 也支持基于 io.Reader/io.Writer 的流式压缩/解压。
+type Codec interface {
+	// Compress 压缩 data 并返回压缩后的结果。
+	Compress(data []byte) ([]byte, error)
+	// Decompress 解压 data 并返回原始内容。
+	Decompress(data []byte) ([]byte, error)
+	// NewWriter 返回一个写入 dst 的压缩 io.WriteCloser。
+	NewWriter(dst io.Writer) io.WriteCloser
+	// NewReader 返回一个从 src 读取并解压的 io.ReadCloser。
+	NewReader(src io.Reader) io.ReadCloser
+}
+
+var (
+	codecMu sync.RWMutex
+	codecs  = make(map[string]Codec)
+)
+
+// Register 将 c 以 name 注册到全局的 codec 列表中，后续可通过 Get(name) 取出。
+// 同名的 Codec 会被覆盖，模仿 archive/zip 的 RegisterCompressor/RegisterDecompressor。
+func Register(name string, c Codec) {
+	codecMu.Lock()
+	defer codecMu.Unlock()
+	codecs[name] = c
+}
+
+// Get 返回 name 对应的 Codec，如果没有注册过则返回 nil。
+func Get(name string) Codec {
+	codecMu.RLock()
+	defer codecMu.RUnlock()
+	return codecs[name]
+}
+
+// flateCodec 是对包级默认 flate 实现的 Codec 包装，保持与历史行为一致。
+type flateCodec struct{}
+
+func (flateCodec) Compress(data []byte) ([]byte, error) {
+	return Compress(data)
+}
+
+func (flateCodec) Decompress(data []byte) ([]byte, error) {
+	return Decompress(data)
+}
+
+func (flateCodec) NewWriter(dst io.Writer) io.WriteCloser {
+	return NewWriter(dst)
+}
+
+func (flateCodec) NewReader(src io.Reader) io.ReadCloser {
+	return NewReader(src)
+}
+
+func init() {
+	Register("flate", flateCodec{})
+}
@@ -0,0 +1,59 @@
+package compress
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+)
+
+func TestCodecRegistryRoundTrip(t *testing.T) {
+	data := []byte("codec registry round trip payload, repeated. codec registry round trip payload")
+
+	for _, name := range []string{"flate", "gzip", "zlib"} {
+		name := name
+		t.Run(name, func(t *testing.T) {
+			codec := Get(name)
+			if codec == nil {
+				t.Fatalf("Get(%q) returned nil", name)
+			}
+
+			c, err := codec.Compress(data)
+			if err != nil {
+				t.Fatalf("Compress: %v", err)
+			}
+			d, err := codec.Decompress(c)
+			if err != nil {
+				t.Fatalf("Decompress: %v", err)
+			}
+			if !bytes.Equal(d, data) {
+				t.Fatalf("round trip mismatch: got %q, want %q", d, data)
+			}
+
+			var buf bytes.Buffer
+			w := codec.NewWriter(&buf)
+			if _, err := w.Write(data); err != nil {
+				t.Fatalf("Write: %v", err)
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("Close writer: %v", err)
+			}
+			r := codec.NewReader(&buf)
+			out, err := ioutil.ReadAll(r)
+			if err != nil {
+				t.Fatalf("ReadAll: %v", err)
+			}
+			if err := r.Close(); err != nil {
+				t.Fatalf("Close reader: %v", err)
+			}
+			if !bytes.Equal(out, data) {
+				t.Fatalf("stream round trip mismatch: got %q, want %q", out, data)
+			}
+		})
+	}
+}
+
+func TestGetUnknownCodec(t *testing.T) {
+	if c := Get("does-not-exist"); c != nil {
+		t.Fatalf("expected nil for unregistered codec, got %v", c)
+	}
+}
@@ -9,15 +9,7 @@ import (
 	"sync"
 )
 
-// Proper usage of a sync.Pool requires each entry to have approximately
-// the same memory cost. To obtain this property when the stored type
-// contains a variably-sized buffer, we add a hard limit on the maximum buffer
-// to place back in the pool.
-//
-// See https://golang.org/issue/23199
-//if cap(p.buf) > 64<<10 {
-//	return
-//}
+// 池化条目的体积上限通过 putBuffer/putSized 强制执行，见 bound.go。
 
 // 压缩buffer对象池
 var CompressBufferPool = sync.Pool{
@@ -69,8 +61,8 @@ func Compress(data []byte) (ret []byte, err error) {
 	}
 	//ret = buffer.Bytes()
 	//buffer.Reset()
-	CompressBufferPool.Put(buffer)
-	CompressWriterPool.Put(writer)
+	putBuffer(&CompressBufferPool, buffer)
+	putSized(&CompressWriterPool, writer, len(data))
 	return
 }
 
@@ -84,13 +76,88 @@ func Decompress(data []byte) ([]byte, error) {
 	if err := reader.(flate.Resetter).Reset(buffer, nil); err != nil {
 		return nil, err
 	}
-	defer reader.Close()
 	out, err := ioutil.ReadAll(reader)
 	if err != nil {
+		reader.Close()
+		return nil, err
+	}
+	if err := reader.Close(); err != nil {
 		return nil, err
 	}
 	//buffer.Reset()
-	CompressBufferPool.Put(buffer)
-	DecompressWriterPool.Put(reader)
+	putBuffer(&CompressBufferPool, buffer)
+	putSized(&DecompressWriterPool, reader, len(out))
 	return out, nil
 }
+
+// streamWriter 包装一个从 CompressWriterPool 取出的 flate.Writer，
+// 在 Close 时完成 flush/close 并将其归还池中。
+type streamWriter struct {
+	w       *flate.Writer
+	written int
+	closed  bool
+}
+
+// NewWriter 返回一个写入 dst 的压缩 io.WriteCloser，
+// 底层复用 CompressWriterPool 中的 flate.Writer，调用方必须在写完后调用 Close
+// 以完成 flush 并将 writer 归还对象池。
+func NewWriter(dst io.Writer) io.WriteCloser {
+	writer := CompressWriterPool.Get().(*flate.Writer)
+	writer.Reset(dst)
+	return &streamWriter{w: writer}
+}
+
+func (s *streamWriter) Write(p []byte) (int, error) {
+	n, err := s.w.Write(p)
+	s.written += n
+	return n, err
+}
+
+// Close flush 并关闭底层 flate.Writer，然后将其归还 CompressWriterPool，
+// 除非本次写入的总量超过了 SetMaxPooledBufferSize 配置的阈值。
+// 重复调用是安全的，第二次及以后的调用为空操作。
+func (s *streamWriter) Close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	err := s.w.Close()
+	putSized(&CompressWriterPool, s.w, s.written)
+	return err
+}
+
+// streamReader 包装一个从 DecompressWriterPool 取出的解压 Reader，
+// 在 Close 时将其归还池中。
+type streamReader struct {
+	r      io.ReadCloser
+	read   int
+	closed bool
+}
+
+// NewReader 返回一个从 src 读取并解压的 io.ReadCloser，
+// 底层复用 DecompressWriterPool 中的 flate.Reader，调用方读取完毕后必须调用
+// Close 将 reader 归还对象池。
+func NewReader(src io.Reader) io.ReadCloser {
+	reader := DecompressWriterPool.Get().(io.ReadCloser)
+	_ = reader.(flate.Resetter).Reset(src, nil)
+	return &streamReader{r: reader}
+}
+
+func (s *streamReader) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	s.read += n
+	return n, err
+}
+
+// Close 关闭底层 reader 并将其归还 DecompressWriterPool，
+// 除非本次读出的总量超过了 SetMaxPooledBufferSize 配置的阈值。
+// 重复调用是安全的，第二次及以后的调用为空操作。
+func (s *streamReader) Close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	err := s.r.Close()
+	putSized(&DecompressWriterPool, s.r, s.read)
+	return err
+}
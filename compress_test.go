@@ -0,0 +1,54 @@
+package compress
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+)
+
+func TestCompressDecompressRoundTrip(t *testing.T) {
+	data := []byte("the quick brown fox jumps over the lazy dog, repeated. the quick brown fox jumps over the lazy dog")
+
+	c, err := Compress(data)
+	if err != nil {
+		t.Fatalf("Compress: %v", err)
+	}
+	d, err := Decompress(c)
+	if err != nil {
+		t.Fatalf("Decompress: %v", err)
+	}
+	if !bytes.Equal(d, data) {
+		t.Fatalf("round trip mismatch: got %q, want %q", d, data)
+	}
+}
+
+func TestNewWriterNewReaderRoundTrip(t *testing.T) {
+	data := []byte("streaming payload that goes through NewWriter/NewReader")
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close writer: %v", err)
+	}
+
+	r := NewReader(&buf)
+	out, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close reader: %v", err)
+	}
+	if !bytes.Equal(out, data) {
+		t.Fatalf("round trip mismatch: got %q, want %q", out, data)
+	}
+}
+
+func TestDecompressInvalidData(t *testing.T) {
+	if _, err := Decompress([]byte{0xFF, 0xFF, 0xFF}); err == nil {
+		t.Fatal("expected error for invalid flate stream, got nil")
+	}
+}
@@ -0,0 +1,146 @@
+package compress
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"sync"
+)
+
+// gzip 压缩器对象池
+var gzipWriterPool = sync.Pool{
+	New: func() interface{} {
+		w, _ := gzip.NewWriterLevel(nil, gzip.BestSpeed)
+		return w
+	},
+}
+
+// gzip 解压器对象池
+var gzipReaderPool = sync.Pool{
+	New: func() interface{} {
+		return new(gzip.Reader)
+	},
+}
+
+// gzipCodec 基于 compress/gzip 实现 Codec 接口。
+type gzipCodec struct{}
+
+// Compress 压缩
+func (gzipCodec) Compress(data []byte) ([]byte, error) {
+	writer := gzipWriterPool.Get().(*gzip.Writer)
+	buffer := CompressBufferPool.Get().(*bytes.Buffer)
+	buffer.Reset()
+	writer.Reset(buffer)
+	if _, err := writer.Write(data); err != nil {
+		writer.Close()
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	ret, err := ioutil.ReadAll(buffer)
+	if err != nil {
+		return nil, err
+	}
+	putBuffer(&CompressBufferPool, buffer)
+	putSized(&gzipWriterPool, writer, len(data))
+	return ret, nil
+}
+
+// Decompress 解压缩
+func (gzipCodec) Decompress(data []byte) ([]byte, error) {
+	reader := gzipReaderPool.Get().(*gzip.Reader)
+	buffer := CompressBufferPool.Get().(*bytes.Buffer)
+	buffer.Reset()
+	buffer.Write(data)
+	if err := reader.Reset(buffer); err != nil {
+		return nil, err
+	}
+	out, err := ioutil.ReadAll(reader)
+	if err != nil {
+		reader.Close()
+		return nil, err
+	}
+	if err := reader.Close(); err != nil {
+		return nil, err
+	}
+	putBuffer(&CompressBufferPool, buffer)
+	putSized(&gzipReaderPool, reader, len(out))
+	return out, nil
+}
+
+type gzipStreamWriter struct {
+	w       *gzip.Writer
+	written int
+	closed  bool
+}
+
+// NewWriter 返回一个写入 dst 的 gzip 压缩 io.WriteCloser，
+// 底层复用 gzipWriterPool，调用方必须在写完后调用 Close 归还对象池。
+func (gzipCodec) NewWriter(dst io.Writer) io.WriteCloser {
+	writer := gzipWriterPool.Get().(*gzip.Writer)
+	writer.Reset(dst)
+	return &gzipStreamWriter{w: writer}
+}
+
+func (s *gzipStreamWriter) Write(p []byte) (int, error) {
+	n, err := s.w.Write(p)
+	s.written += n
+	return n, err
+}
+
+func (s *gzipStreamWriter) Close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	err := s.w.Close()
+	putSized(&gzipWriterPool, s.w, s.written)
+	return err
+}
+
+type gzipStreamReader struct {
+	r      *gzip.Reader
+	read   int
+	closed bool
+	// err holds a failure from Reset (e.g. an invalid gzip header); the
+	// underlying *gzip.Reader's decompressor is never set in that case, so
+	// s.r must not be touched until the caller has seen this error.
+	err error
+}
+
+// NewReader 返回一个从 src 读取并解压的 io.ReadCloser，
+// 底层复用 gzipReaderPool，调用方读取完毕后必须调用 Close 归还对象池。
+// 若 src 的 gzip 头非法，错误会延迟到第一次 Read 时返回。
+func (gzipCodec) NewReader(src io.Reader) io.ReadCloser {
+	reader := gzipReaderPool.Get().(*gzip.Reader)
+	err := reader.Reset(src)
+	return &gzipStreamReader{r: reader, err: err}
+}
+
+func (s *gzipStreamReader) Read(p []byte) (int, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	n, err := s.r.Read(p)
+	s.read += n
+	return n, err
+}
+
+func (s *gzipStreamReader) Close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	if s.err != nil {
+		return s.err
+	}
+	err := s.r.Close()
+	putSized(&gzipReaderPool, s.r, s.read)
+	return err
+}
+
+func init() {
+	Register("gzip", gzipCodec{})
+}
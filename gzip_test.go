@@ -0,0 +1,26 @@
+package compress
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestGzipCodecDecompressInvalidData(t *testing.T) {
+	if _, err := (gzipCodec{}).Decompress([]byte{0, 1, 2}); err == nil {
+		t.Fatal("expected error for invalid gzip data, got nil")
+	}
+}
+
+// TestGzipCodecNewReaderInvalidHeaderDoesNotPanic guards against a
+// regression where an invalid gzip header left the pooled *gzip.Reader's
+// decompressor nil, and Close (which unconditionally calls
+// decompressor.Close()) panicked instead of returning the header error.
+func TestGzipCodecNewReaderInvalidHeaderDoesNotPanic(t *testing.T) {
+	r := (gzipCodec{}).NewReader(bytes.NewReader([]byte{0, 1, 2}))
+	if _, err := r.Read(make([]byte, 8)); err == nil {
+		t.Fatal("expected error reading invalid gzip header, got nil")
+	}
+	if err := r.Close(); err == nil {
+		t.Fatal("expected error closing reader with invalid gzip header, got nil")
+	}
+}
@@ -0,0 +1,131 @@
+package compress
+
+import (
+	"bytes"
+	"compress/flate"
+	"fmt"
+	"io/ioutil"
+	"sync"
+)
+
+// validateLevel 校验 level 是否为 flate 接受的压缩级别
+// （flate.HuffmanOnly..flate.BestCompression，即 -2..9）。flate.NewWriter
+// 对非法 level 会返回错误，但 sync.Pool 的 New 无法向调用方传递错误，
+// 因此需要在把 level 交给 levelWriterPool 之前提前校验，否则池会缓存一个
+// nil *flate.Writer，之后每次 Get() 都会在 Reset/Write 时空指针 panic。
+func validateLevel(level int) error {
+	if level < flate.HuffmanOnly || level > flate.BestCompression {
+		return fmt.Errorf("compress: invalid flate level %d", level)
+	}
+	return nil
+}
+
+// levelWriterPools 为每个压缩级别维护一个独立的 flate.Writer 对象池，
+// 使得 CompressWithLevel 在复用 writer 的同时不必在调用间反复切换级别
+// （flate.Writer 不支持运行时更改级别）。
+var (
+	levelWriterPoolsMu sync.RWMutex
+	levelWriterPools   = make(map[int]*sync.Pool)
+)
+
+// levelWriterPool 返回 level 对应的 writer 对象池，不存在则创建。
+func levelWriterPool(level int) *sync.Pool {
+	levelWriterPoolsMu.RLock()
+	pool, ok := levelWriterPools[level]
+	levelWriterPoolsMu.RUnlock()
+	if ok {
+		return pool
+	}
+
+	levelWriterPoolsMu.Lock()
+	defer levelWriterPoolsMu.Unlock()
+	if pool, ok := levelWriterPools[level]; ok {
+		return pool
+	}
+	pool = &sync.Pool{
+		New: func() interface{} {
+			w, _ := flate.NewWriter(nil, level)
+			return w
+		},
+	}
+	levelWriterPools[level] = pool
+	return pool
+}
+
+// CompressWithLevel 以给定的 flate 压缩级别压缩 data，每个级别复用各自的
+// flate.Writer 对象池，而不是像 Compress 那样固定使用 flate.BestSpeed。
+func CompressWithLevel(data []byte, level int) (ret []byte, err error) {
+	if err = validateLevel(level); err != nil {
+		return nil, err
+	}
+	pool := levelWriterPool(level)
+	writer := pool.Get().(*flate.Writer)
+	buffer := CompressBufferPool.Get().(*bytes.Buffer)
+	buffer.Reset()
+	writer.Reset(buffer)
+	if _, err = writer.Write(data); err != nil {
+		writer.Close()
+		return nil, err
+	}
+	if err = writer.Close(); err != nil {
+		return nil, err
+	}
+	ret, err = ioutil.ReadAll(buffer)
+	if err != nil {
+		return nil, err
+	}
+	putBuffer(&CompressBufferPool, buffer)
+	putSized(pool, writer, len(data))
+	return
+}
+
+// CompressWithDict 以给定的压缩级别和预设字典压缩 data。预设字典对于短小、
+// 重复的 RPC/JSON 帧尤其有用：共享的前导内容可以显著提高压缩率，这也是
+// flate 的 Reset 示例中强调的用法。由于 flate.Writer 的字典在创建时固化、
+// 无法通过 Reset 更换，带字典的 writer 不进入 levelWriterPools，
+// 每次调用时现场创建；不带字典（dict 为空）时退化为 CompressWithLevel。
+func CompressWithDict(data, dict []byte, level int) ([]byte, error) {
+	if len(dict) == 0 {
+		return CompressWithLevel(data, level)
+	}
+
+	buffer := CompressBufferPool.Get().(*bytes.Buffer)
+	buffer.Reset()
+	writer, err := flate.NewWriterDict(buffer, level, dict)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = writer.Write(data); err != nil {
+		writer.Close()
+		return nil, err
+	}
+	if err = writer.Close(); err != nil {
+		return nil, err
+	}
+	ret, err := ioutil.ReadAll(buffer)
+	if err != nil {
+		return nil, err
+	}
+	putBuffer(&CompressBufferPool, buffer)
+	return ret, nil
+}
+
+// DecompressWithDict 使用预设字典 dict 解压 data，dict 必须与压缩时
+// CompressWithDict 所用的字典一致；dict 为空时等价于 Decompress。
+func DecompressWithDict(data, dict []byte) ([]byte, error) {
+	if len(dict) == 0 {
+		return Decompress(data)
+	}
+
+	buffer := CompressBufferPool.Get().(*bytes.Buffer)
+	buffer.Reset()
+	buffer.Write(data)
+	reader := flate.NewReaderDict(buffer, dict)
+	defer reader.Close()
+	out, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	putBuffer(&CompressBufferPool, buffer)
+	return out, nil
+}
@@ -0,0 +1,67 @@
+package compress
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCompressWithLevelRoundTrip(t *testing.T) {
+	data := []byte("compress with level round trip payload, repeated. compress with level round trip payload")
+
+	for _, level := range []int{-2, -1, 0, 1, 6, 9} {
+		level := level
+		c, err := CompressWithLevel(data, level)
+		if err != nil {
+			t.Fatalf("CompressWithLevel(level=%d): %v", level, err)
+		}
+		d, err := Decompress(c)
+		if err != nil {
+			t.Fatalf("Decompress(level=%d): %v", level, err)
+		}
+		if !bytes.Equal(d, data) {
+			t.Fatalf("round trip mismatch for level %d", level)
+		}
+	}
+}
+
+// TestCompressWithLevelInvalidLevel guards against a regression where an
+// out-of-range level silently produced a nil pooled *flate.Writer, which
+// then panicked on the next Reset call instead of returning an error.
+func TestCompressWithLevelInvalidLevel(t *testing.T) {
+	if _, err := CompressWithLevel([]byte("hello world"), 100); err == nil {
+		t.Fatal("expected error for out-of-range level, got nil")
+	}
+}
+
+func TestCompressWithDictRoundTrip(t *testing.T) {
+	dict := []byte("shared preset dictionary prelude")
+	data := []byte("shared preset dictionary prelude plus the actual payload bytes")
+
+	c, err := CompressWithDict(data, dict, 6)
+	if err != nil {
+		t.Fatalf("CompressWithDict: %v", err)
+	}
+	d, err := DecompressWithDict(c, dict)
+	if err != nil {
+		t.Fatalf("DecompressWithDict: %v", err)
+	}
+	if !bytes.Equal(d, data) {
+		t.Fatal("round trip mismatch")
+	}
+}
+
+func TestCompressWithDictEmptyDictDelegatesToLevel(t *testing.T) {
+	data := []byte("no dictionary here")
+
+	c, err := CompressWithDict(data, nil, 6)
+	if err != nil {
+		t.Fatalf("CompressWithDict: %v", err)
+	}
+	d, err := DecompressWithDict(c, nil)
+	if err != nil {
+		t.Fatalf("DecompressWithDict: %v", err)
+	}
+	if !bytes.Equal(d, data) {
+		t.Fatal("round trip mismatch")
+	}
+}
@@ -0,0 +1,244 @@
+package compress
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"sync"
+)
+
+// DefaultMaxInFlightBlocks 限制并行压缩/解压时同时在途的块数量，
+// 用于在多核机器上获得并发收益的同时避免一次性把过多缓冲区驻留在内存中，
+// 类似 pgzip 的基于块的并发模型。
+const DefaultMaxInFlightBlocks = 16
+
+// errBlockTooShort 表示帧头不完整，数据被截断。
+var errBlockTooShort = errors.New("compress: truncated block frame")
+
+// compressBlock 压缩单个块，复用 CompressWriterPool 中的 flate.Writer。
+func compressBlock(block []byte) ([]byte, error) {
+	return Compress(block)
+}
+
+// decompressBlock 解压单个块，复用 DecompressWriterPool 中的 flate.Reader。
+func decompressBlock(block []byte) ([]byte, error) {
+	return Decompress(block)
+}
+
+// CompressParallel 将 data 切分为大小为 blockSize 的块，使用 workers 个
+// 并发的 worker（通过 CompressWriterPool 复用 flate.Writer）压缩每个块，
+// 并输出一串帧：每帧由 4 字节大端长度前缀加压缩后的数据组成，使
+// DecompressParallel 能够按帧并发解压。同时在途的块数不超过
+// DefaultMaxInFlightBlocks，以控制内存占用。
+func CompressParallel(data []byte, blockSize, workers int) ([]byte, error) {
+	if blockSize <= 0 {
+		return nil, errors.New("compress: blockSize must be positive")
+	}
+	if workers <= 0 {
+		workers = 1
+	}
+
+	var blocks [][]byte
+	for off := 0; off < len(data); off += blockSize {
+		end := off + blockSize
+		if end > len(data) {
+			end = len(data)
+		}
+		blocks = append(blocks, data[off:end])
+	}
+	if len(blocks) == 0 {
+		return nil, nil
+	}
+
+	results := make([][]byte, len(blocks))
+	errs := make([]error, len(blocks))
+
+	sem := make(chan struct{}, DefaultMaxInFlightBlocks)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				out, err := compressBlock(blocks[idx])
+				results[idx] = out
+				errs[idx] = err
+				<-sem
+			}
+		}()
+	}
+
+	for idx := range blocks {
+		sem <- struct{}{}
+		jobs <- idx
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	buffer := CompressBufferPool.Get().(*bytes.Buffer)
+	buffer.Reset()
+	defer putBuffer(&CompressBufferPool, buffer)
+
+	var header [4]byte
+	for _, r := range results {
+		binary.BigEndian.PutUint32(header[:], uint32(len(r)))
+		buffer.Write(header[:])
+		buffer.Write(r)
+	}
+
+	out := make([]byte, buffer.Len())
+	copy(out, buffer.Bytes())
+	return out, nil
+}
+
+// DecompressParallel 解析 CompressParallel 生成的帧序列，使用 workers 个
+// 并发 worker 解压每一帧，然后按原始顺序拼接结果。
+func DecompressParallel(data []byte, workers int) ([]byte, error) {
+	if workers <= 0 {
+		workers = 1
+	}
+
+	var blocks [][]byte
+	for off := 0; off < len(data); {
+		if off+4 > len(data) {
+			return nil, errBlockTooShort
+		}
+		n := int(binary.BigEndian.Uint32(data[off : off+4]))
+		off += 4
+		if off+n > len(data) {
+			return nil, errBlockTooShort
+		}
+		blocks = append(blocks, data[off:off+n])
+		off += n
+	}
+	if len(blocks) == 0 {
+		return nil, nil
+	}
+
+	results := make([][]byte, len(blocks))
+	errs := make([]error, len(blocks))
+
+	sem := make(chan struct{}, DefaultMaxInFlightBlocks)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				out, err := decompressBlock(blocks[idx])
+				results[idx] = out
+				errs[idx] = err
+				<-sem
+			}
+		}()
+	}
+
+	for idx := range blocks {
+		sem <- struct{}{}
+		jobs <- idx
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var total int
+	for _, r := range results {
+		total += len(r)
+	}
+	out := make([]byte, 0, total)
+	for _, r := range results {
+		out = append(out, r...)
+	}
+	return out, nil
+}
+
+// parallelWriter 是 CompressParallel 的流式版本：数据被累积到 blockSize
+// 大小后逐块压缩并写出帧，使内存占用不超过一个块的大小。
+// 各块在调用 Write/Close 的 goroutine 中依次压缩，不使用并发 worker。
+type parallelWriter struct {
+	dst       io.Writer
+	blockSize int
+	pending   []byte
+	writeErr  error
+}
+
+// NewParallelWriter 返回一个流式的分块压缩 io.WriteCloser，数据按 blockSize
+// 切分、逐块压缩后写入 dst，帧格式与 CompressParallel 一致，
+// 因此输出可直接交给 DecompressParallel 并发解压。
+func NewParallelWriter(dst io.Writer, blockSize int) io.WriteCloser {
+	if blockSize <= 0 {
+		blockSize = 1 << 20
+	}
+	return &parallelWriter{
+		dst:       dst,
+		blockSize: blockSize,
+	}
+}
+
+func (p *parallelWriter) Write(b []byte) (int, error) {
+	total := len(b)
+	for len(b) > 0 {
+		n := p.blockSize - len(p.pending)
+		if n > len(b) {
+			n = len(b)
+		}
+		p.pending = append(p.pending, b[:n]...)
+		b = b[n:]
+		if len(p.pending) == p.blockSize {
+			if err := p.flushBlock(p.pending); err != nil {
+				return 0, err
+			}
+			p.pending = p.pending[:0]
+		}
+	}
+	return total, nil
+}
+
+// flushBlock 压缩并写出一个块。
+func (p *parallelWriter) flushBlock(block []byte) error {
+	if p.writeErr != nil {
+		return p.writeErr
+	}
+	out, err := compressBlock(block)
+	if err != nil {
+		p.writeErr = err
+		return err
+	}
+	var header [4]byte
+	binary.BigEndian.PutUint32(header[:], uint32(len(out)))
+	if _, err := p.dst.Write(header[:]); err != nil {
+		p.writeErr = err
+		return err
+	}
+	if _, err := p.dst.Write(out); err != nil {
+		p.writeErr = err
+		return err
+	}
+	return nil
+}
+
+// Close 压缩并写出剩余的不足一个 blockSize 的数据。
+func (p *parallelWriter) Close() error {
+	if len(p.pending) == 0 {
+		return p.writeErr
+	}
+	block := p.pending
+	p.pending = nil
+	return p.flushBlock(block)
+}
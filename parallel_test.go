@@ -0,0 +1,59 @@
+package compress
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCompressDecompressParallelRoundTrip(t *testing.T) {
+	data := bytes.Repeat([]byte("compress parallel round trip block. "), 500)
+
+	c, err := CompressParallel(data, 64, 4)
+	if err != nil {
+		t.Fatalf("CompressParallel: %v", err)
+	}
+	d, err := DecompressParallel(c, 4)
+	if err != nil {
+		t.Fatalf("DecompressParallel: %v", err)
+	}
+	if !bytes.Equal(d, data) {
+		t.Fatal("round trip mismatch")
+	}
+}
+
+func TestCompressParallelEmptyInput(t *testing.T) {
+	c, err := CompressParallel(nil, 64, 4)
+	if err != nil {
+		t.Fatalf("CompressParallel: %v", err)
+	}
+	if len(c) != 0 {
+		t.Fatalf("expected empty output for empty input, got %d bytes", len(c))
+	}
+}
+
+func TestDecompressParallelTruncatedFrame(t *testing.T) {
+	if _, err := DecompressParallel([]byte{0, 0, 0}, 4); err == nil {
+		t.Fatal("expected error for truncated frame header, got nil")
+	}
+}
+
+func TestNewParallelWriterRoundTrip(t *testing.T) {
+	data := bytes.Repeat([]byte("streaming parallel writer block. "), 200)
+
+	var buf bytes.Buffer
+	w := NewParallelWriter(&buf, 64)
+	if _, err := w.Write(data); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	out, err := DecompressParallel(buf.Bytes(), 4)
+	if err != nil {
+		t.Fatalf("DecompressParallel: %v", err)
+	}
+	if !bytes.Equal(out, data) {
+		t.Fatal("round trip mismatch")
+	}
+}
@@ -0,0 +1,77 @@
+package compress
+
+import "errors"
+
+// minCompressIfSmallerSize 是 CompressIfSmaller 考虑压缩的最小输入体积；
+// 更小的数据直接透传，压缩开销不值得。
+const minCompressIfSmallerSize = 128
+
+// minCompressIfSmallerSavings 是 CompressIfSmaller 认为"有意义"的最小压缩率，
+// 低于该比例时视为压缩收益不明显，直接透传原始数据。
+const minCompressIfSmallerSavings = 0.05
+
+// frameRaw/frameCompressed 是 CompressIfSmaller 输出前缀的一字节帧头，
+// 供 DecompressIfSmaller 区分透传数据与 flate 压缩数据。
+const (
+	frameRaw        byte = 0
+	frameCompressed byte = 1
+)
+
+// errEmptyFrame 表示 DecompressIfSmaller 收到的数据不含帧头字节。
+var errEmptyFrame = errors.New("compress: empty frame")
+
+// errUnknownFrame 表示帧头字节既不是 frameRaw 也不是 frameCompressed。
+var errUnknownFrame = errors.New("compress: unknown frame header")
+
+// CompressIfSmaller 尝试压缩 data，但当压缩收益不明显时（收益低于
+// minCompressIfSmallerSavings，或 data 本身小于 minCompressIfSmallerSize）
+// 直接返回原始数据并将 compressed 置为 false。这避免了对已经压缩过的数据
+// （图片、预先 gzip 过的静态资源等）反复走一遍 DEFLATE 的开销，这也是
+// Compress 目前总会付出的成本。返回值带有一字节帧头，供 DecompressIfSmaller
+// 识别透传情形。
+func CompressIfSmaller(data []byte) (out []byte, compressed bool, err error) {
+	if len(data) < minCompressIfSmallerSize {
+		return passthroughFrame(data), false, nil
+	}
+
+	c, err := Compress(data)
+	if err != nil {
+		return nil, false, err
+	}
+
+	savings := 1 - float64(len(c))/float64(len(data))
+	if savings < minCompressIfSmallerSavings {
+		return passthroughFrame(data), false, nil
+	}
+
+	out = make([]byte, 0, len(c)+1)
+	out = append(out, frameCompressed)
+	out = append(out, c...)
+	return out, true, nil
+}
+
+func passthroughFrame(data []byte) []byte {
+	out := make([]byte, 0, len(data)+1)
+	out = append(out, frameRaw)
+	out = append(out, data...)
+	return out
+}
+
+// DecompressIfSmaller 解析 CompressIfSmaller 产生的帧：透传帧直接返回原始
+// 数据，压缩帧则交给 Decompress 解压。
+func DecompressIfSmaller(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, errEmptyFrame
+	}
+	header, body := data[0], data[1:]
+	switch header {
+	case frameRaw:
+		out := make([]byte, len(body))
+		copy(out, body)
+		return out, nil
+	case frameCompressed:
+		return Decompress(body)
+	default:
+		return nil, errUnknownFrame
+	}
+}
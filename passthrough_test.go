@@ -0,0 +1,80 @@
+package compress
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+func TestCompressIfSmallerPassthroughForShortInput(t *testing.T) {
+	data := []byte("hi")
+
+	out, compressed, err := CompressIfSmaller(data)
+	if err != nil {
+		t.Fatalf("CompressIfSmaller: %v", err)
+	}
+	if compressed {
+		t.Fatal("expected passthrough for input below minCompressIfSmallerSize")
+	}
+	back, err := DecompressIfSmaller(out)
+	if err != nil {
+		t.Fatalf("DecompressIfSmaller: %v", err)
+	}
+	if !bytes.Equal(back, data) {
+		t.Fatalf("round trip mismatch: got %q, want %q", back, data)
+	}
+}
+
+func TestCompressIfSmallerPassthroughForIncompressibleInput(t *testing.T) {
+	// Pseudo-random bytes (fixed seed for determinism) won't compress
+	// meaningfully and are above the size threshold, forcing the
+	// savings-ratio check to trigger.
+	data := make([]byte, 2048)
+	rand.New(rand.NewSource(1)).Read(data)
+
+	out, compressed, err := CompressIfSmaller(data)
+	if err != nil {
+		t.Fatalf("CompressIfSmaller: %v", err)
+	}
+	if compressed {
+		t.Fatal("expected passthrough for incompressible input")
+	}
+	back, err := DecompressIfSmaller(out)
+	if err != nil {
+		t.Fatalf("DecompressIfSmaller: %v", err)
+	}
+	if !bytes.Equal(back, data) {
+		t.Fatal("round trip mismatch")
+	}
+}
+
+func TestCompressIfSmallerCompressesRepetitiveInput(t *testing.T) {
+	data := bytes.Repeat([]byte("compressible data compressible data compressible data "), 20)
+
+	out, compressed, err := CompressIfSmaller(data)
+	if err != nil {
+		t.Fatalf("CompressIfSmaller: %v", err)
+	}
+	if !compressed {
+		t.Fatal("expected compression for highly repetitive input")
+	}
+	back, err := DecompressIfSmaller(out)
+	if err != nil {
+		t.Fatalf("DecompressIfSmaller: %v", err)
+	}
+	if !bytes.Equal(back, data) {
+		t.Fatal("round trip mismatch")
+	}
+}
+
+func TestDecompressIfSmallerRejectsUnknownFrameHeader(t *testing.T) {
+	if _, err := DecompressIfSmaller([]byte{0x7F, 'x', 'y'}); err == nil {
+		t.Fatal("expected error for unknown frame header, got nil")
+	}
+}
+
+func TestDecompressIfSmallerRejectsEmptyInput(t *testing.T) {
+	if _, err := DecompressIfSmaller(nil); err == nil {
+		t.Fatal("expected error for empty input, got nil")
+	}
+}
@@ -0,0 +1,163 @@
+package compress
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"io/ioutil"
+	"sync"
+)
+
+// zlib 压缩器对象池
+var zlibWriterPool = sync.Pool{
+	New: func() interface{} {
+		w, _ := zlib.NewWriterLevel(nil, zlib.BestSpeed)
+		return w
+	},
+}
+
+// zlib 解压器对象池
+var zlibReaderPool = sync.Pool{
+	New: func() interface{} {
+		return nil
+	},
+}
+
+// zlibCodec 基于 compress/zlib 实现 Codec 接口。
+type zlibCodec struct{}
+
+// Compress 压缩
+func (zlibCodec) Compress(data []byte) ([]byte, error) {
+	writer := zlibWriterPool.Get().(*zlib.Writer)
+	buffer := CompressBufferPool.Get().(*bytes.Buffer)
+	buffer.Reset()
+	writer.Reset(buffer)
+	if _, err := writer.Write(data); err != nil {
+		writer.Close()
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	ret, err := ioutil.ReadAll(buffer)
+	if err != nil {
+		return nil, err
+	}
+	putBuffer(&CompressBufferPool, buffer)
+	putSized(&zlibWriterPool, writer, len(data))
+	return ret, nil
+}
+
+// Decompress 解压缩
+func (zlibCodec) Decompress(data []byte) ([]byte, error) {
+	buffer := CompressBufferPool.Get().(*bytes.Buffer)
+	buffer.Reset()
+	buffer.Write(data)
+
+	cached := zlibReaderPool.Get()
+	var reader io.ReadCloser
+	var err error
+	if cached != nil {
+		reader = cached.(io.ReadCloser)
+		err = reader.(zlib.Resetter).Reset(buffer, nil)
+	} else {
+		reader, err = zlib.NewReader(buffer)
+	}
+	if err != nil {
+		return nil, err
+	}
+	out, err := ioutil.ReadAll(reader)
+	if err != nil {
+		reader.Close()
+		return nil, err
+	}
+	if err := reader.Close(); err != nil {
+		return nil, err
+	}
+	putBuffer(&CompressBufferPool, buffer)
+	putSized(&zlibReaderPool, reader, len(out))
+	return out, nil
+}
+
+type zlibStreamWriter struct {
+	w       *zlib.Writer
+	written int
+	closed  bool
+}
+
+// NewWriter 返回一个写入 dst 的 zlib 压缩 io.WriteCloser，
+// 底层复用 zlibWriterPool，调用方必须在写完后调用 Close 归还对象池。
+func (zlibCodec) NewWriter(dst io.Writer) io.WriteCloser {
+	writer := zlibWriterPool.Get().(*zlib.Writer)
+	writer.Reset(dst)
+	return &zlibStreamWriter{w: writer}
+}
+
+func (s *zlibStreamWriter) Write(p []byte) (int, error) {
+	n, err := s.w.Write(p)
+	s.written += n
+	return n, err
+}
+
+func (s *zlibStreamWriter) Close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	err := s.w.Close()
+	putSized(&zlibWriterPool, s.w, s.written)
+	return err
+}
+
+type zlibStreamReader struct {
+	r      io.ReadCloser
+	read   int
+	closed bool
+	// err holds a failure from NewReader (e.g. an invalid zlib header) that
+	// couldn't be returned directly because NewReader must return an
+	// io.ReadCloser; it surfaces on the first Read instead.
+	err error
+}
+
+// NewReader 返回一个从 src 读取并解压的 io.ReadCloser，
+// 底层复用 zlibReaderPool，调用方读取完毕后必须调用 Close 归还对象池。
+// zlib 解压器无法在没有既有 Reader 的情况下预先创建，因此池为空时现场创建一个；
+// 若现场创建失败（例如 src 不是合法的 zlib 流），错误会延迟到第一次 Read 时返回。
+func (zlibCodec) NewReader(src io.Reader) io.ReadCloser {
+	cached := zlibReaderPool.Get()
+	var reader io.ReadCloser
+	var err error
+	if cached != nil {
+		reader = cached.(io.ReadCloser)
+		err = reader.(zlib.Resetter).Reset(src, nil)
+	} else {
+		reader, err = zlib.NewReader(src)
+	}
+	return &zlibStreamReader{r: reader, err: err}
+}
+
+func (s *zlibStreamReader) Read(p []byte) (int, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	n, err := s.r.Read(p)
+	s.read += n
+	return n, err
+}
+
+func (s *zlibStreamReader) Close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	if s.r == nil {
+		return s.err
+	}
+	err := s.r.Close()
+	putSized(&zlibReaderPool, s.r, s.read)
+	return err
+}
+
+func init() {
+	Register("zlib", zlibCodec{})
+}
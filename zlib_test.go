@@ -0,0 +1,25 @@
+package compress
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestZlibCodecDecompressInvalidData(t *testing.T) {
+	if _, err := (zlibCodec{}).Decompress([]byte{0xFF, 0xFF, 0xFF}); err == nil {
+		t.Fatal("expected error for invalid zlib data, got nil")
+	}
+}
+
+// TestZlibCodecNewReaderInvalidHeaderDoesNotPanic guards against the
+// nil-reader regression fixed for zlibStreamReader: an invalid zlib header
+// must surface as an error from Read/Close, not a nil-pointer panic.
+func TestZlibCodecNewReaderInvalidHeaderDoesNotPanic(t *testing.T) {
+	r := (zlibCodec{}).NewReader(bytes.NewReader([]byte{0xFF, 0xFF, 0xFF}))
+	if _, err := r.Read(make([]byte, 8)); err == nil {
+		t.Fatal("expected error reading invalid zlib header, got nil")
+	}
+	if err := r.Close(); err == nil {
+		t.Fatal("expected error closing reader with invalid zlib header, got nil")
+	}
+}